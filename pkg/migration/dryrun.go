@@ -0,0 +1,74 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dentech-floss/orm/pkg/orm"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// ErrDryRunNotSupported - the database can't roll back DDL statements (MySQL commits them implicitly)
+var ErrDryRunNotSupported = errors.New("migration: dry run is not supported since DDL statements can't be rolled back")
+
+// DryRunMigrations - run the pending migrations inside a transaction that is always rolled back
+// and return the executed SQL statements so they can be reviewed before the real run.
+// Only supported by databases with transactional DDL (like Postgres and SQLite), since MySQL
+// implicitly commits DDL statements which would make the dry run apply them for real.
+func (m Migration) DryRunMigrations(
+	migrations []*gormigrate.Migration,
+) ([]string, error) {
+	if m.db.Dialector.Name() == "mysql" {
+		return nil, ErrDryRunNotSupported
+	}
+	if err := validateMigrationIDs(migrations); err != nil {
+		return nil, err
+	}
+
+	recorder := &sqlRecorder{}
+	tx := m.db.Session(&gorm.Session{Logger: recorder, Context: orm.WithoutQueryTimeout(context.Background())}).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer tx.Rollback()
+
+	options := *m.options
+	options.UseTransaction = false // already within the dry run transaction
+	gm := gormigrate.New(tx, &options, migrations)
+	if m.initSchema != nil {
+		gm.InitSchema(m.initSchema)
+	}
+
+	if err := gm.Migrate(); err != nil {
+		return recorder.statements, err
+	}
+
+	return recorder.statements, nil
+}
+
+// Logger recording all executed SQL statements
+type sqlRecorder struct {
+	mu         sync.Mutex
+	statements []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *sqlRecorder) Info(context.Context, string, ...interface{}) {}
+
+func (r *sqlRecorder) Warn(context.Context, string, ...interface{}) {}
+
+func (r *sqlRecorder) Error(context.Context, string, ...interface{}) {}
+
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, sql)
+}
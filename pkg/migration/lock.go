@@ -0,0 +1,70 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// WithAdvisoryLock - returns a copy of the migration which holds a database wide advisory lock
+// with the given name while migrating/rolling back, so other instances starting at the same time
+// block until the lock is released and then find the migrations already applied. Uses GET_LOCK
+// on MySQL and pg_advisory_lock on Postgres, while SQLite (being in-process) is not locked.
+func (m Migration) WithAdvisoryLock(name string) *Migration {
+	m.advisoryLock = name
+	return &m
+}
+
+// Run fn while holding the advisory lock (if any), the lock is taken on a dedicated
+// connection since it belongs to the session and must be released on the same one
+func (m Migration) withAdvisoryLock(ctx context.Context, fn func() error) error {
+	if m.advisoryLock == "" {
+		return fn()
+	}
+
+	var lockSQL, unlockSQL string
+	switch m.db.Dialector.Name() {
+	case "mysql":
+		lockSQL, unlockSQL = "SELECT GET_LOCK(?, -1) = 1", "SELECT RELEASE_LOCK(?)"
+	case "postgres":
+		// pg_advisory_lock returns void, which can't be scanned, so select true once it returns
+		lockSQL, unlockSQL = "SELECT true FROM pg_advisory_lock(hashtext($1))", "SELECT pg_advisory_unlock(hashtext($1))"
+	default:
+		return fn()
+	}
+
+	sqlDB, err := m.db.SQLDB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked sql.NullBool
+	if err := conn.QueryRowContext(ctx, lockSQL, m.advisoryLock).Scan(&locked); err != nil {
+		// the lock may have been taken before the error (like the context being canceled), so
+		// don't put the connection back in the pool still holding it
+		discardConn(conn)
+		return fmt.Errorf("migration: failed to acquire advisory lock %q: %w", m.advisoryLock, err)
+	}
+	// true once acquired, while GET_LOCK returns 0 (timeout) or NULL (error) otherwise
+	if !locked.Valid || !locked.Bool {
+		return fmt.Errorf("migration: failed to acquire advisory lock %q", m.advisoryLock)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), unlockSQL, m.advisoryLock); err != nil {
+			discardConn(conn)
+		}
+	}()
+
+	return fn()
+}
+
+// Close the connection instead of returning it to the pool, since it may still hold the lock
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+}
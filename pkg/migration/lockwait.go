@@ -0,0 +1,88 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dentech-floss/orm/pkg/orm"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ErrLockWaitTimeout - a migration gave up waiting for a lock held by another session
+var ErrLockWaitTimeout = errors.New("migration: lock wait timeout exceeded")
+
+// WithLockWaitTimeout - returns a copy of the migration whose statements wait at most the timeout
+// for the locks they need (like the metadata lock of a table used by a long-running query), after
+// which the migration fails with an ErrLockWaitTimeout instead of hanging the whole deploy. Sets
+// lock_wait_timeout and innodb_lock_wait_timeout (in whole seconds) on MySQL and lock_timeout on
+// Postgres. The migration then runs on a dedicated connection, which statements routed to
+// ReplicaHosts (without UseTransaction) don't use.
+func (m Migration) WithLockWaitTimeout(timeout time.Duration) *Migration {
+	m.lockWaitTimeout = timeout
+	return &m
+}
+
+// Run fn with the db to migrate with, which is pinned to a dedicated connection having the lock
+// wait timeout set (and reset afterwards since the connection is returned to the pool) if any
+func (m Migration) withLockWaitTimeout(ctx context.Context, fn func(db *gorm.DB) error) error {
+	db := m.db.DB.WithContext(orm.WithoutQueryTimeout(ctx))
+	if m.lockWaitTimeout <= 0 {
+		return fn(db)
+	}
+
+	var setSQL, resetSQL string
+	switch m.db.Dialector.Name() {
+	case "mysql":
+		seconds := max(int(math.Ceil(m.lockWaitTimeout.Seconds())), 1)
+		setSQL = fmt.Sprintf("SET SESSION lock_wait_timeout = %d, innodb_lock_wait_timeout = %d", seconds, seconds)
+		resetSQL = "SET SESSION lock_wait_timeout = DEFAULT, innodb_lock_wait_timeout = DEFAULT"
+	case "postgres":
+		setSQL = fmt.Sprintf("SET lock_timeout = %d", max(m.lockWaitTimeout.Milliseconds(), 1))
+		resetSQL = "RESET lock_timeout"
+	default:
+		return fn(db)
+	}
+
+	sqlDB, err := m.db.SQLDB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, setSQL); err != nil {
+		return fmt.Errorf("migration: failed to set the lock wait timeout: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), resetSQL)
+
+	db.Statement.ConnPool = conn
+	if err := fn(db); err != nil {
+		if isLockWaitTimeout(err) {
+			return fmt.Errorf("%w after %s: %w", ErrLockWaitTimeout, m.lockWaitTimeout, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func isLockWaitTimeout(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1205 // ER_LOCK_WAIT_TIMEOUT
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "55P03" // lock_not_available
+	}
+
+	return false
+}
@@ -1,14 +1,38 @@
 package migration
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/dentech-floss/orm/pkg/orm"
 	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrMigrationNotApplied - the migration to roll back to (or one expected by AssertAllApplied) has never been applied
+var ErrMigrationNotApplied = errors.New("migration: migration has not been applied")
+
+// ErrMigrationIrreversible - an applied migration to roll back has no Rollback func
+var ErrMigrationIrreversible = errors.New("migration: migration can't be rolled back")
+
 // Migration - migration object structure
 type Migration struct {
-	db      *orm.Orm
-	options *gormigrate.Options
+	db               *orm.Orm
+	options          *gormigrate.Options
+	advisoryLock     string
+	migrationTimeout time.Duration
+	lockWaitTimeout  time.Duration
+	initSchema       gormigrate.InitSchemaFunc
+}
+
+// MigrationStatus - whether a migration has been applied or is pending
+type MigrationStatus struct {
+	ID      string
+	Applied bool
 }
 
 // Option - type for function for options change
@@ -32,30 +56,251 @@ func NewMigration(db *orm.Orm, options ...Option) *Migration {
 func (m Migration) RunMigrations(
 	migrations []*gormigrate.Migration,
 ) error {
-	gm := gormigrate.New(m.db.DB, m.options, migrations)
+	return m.RunMigrationsContext(context.Background(), migrations)
+}
+
+// RunMigrationsContext - apply migrations that weren't applied before, aborted if the context is done.
+// Nothing is applied if any of the migrations has an empty or duplicated ID. The run is traced
+// as a "db.migrate" span with a child "db.migration" span per applied migration.
+func (m Migration) RunMigrationsContext(
+	ctx context.Context,
+	migrations []*gormigrate.Migration,
+) (err error) {
+	ctx, span := startMigrateSpan(ctx)
+	defer func() { endSpan(span, err) }()
 
-	if err := gm.Migrate(); err != nil {
+	if err := validateMigrationIDs(migrations); err != nil {
 		return err
 	}
 
-	return nil
+	return m.withAdvisoryLock(ctx, func() error {
+		return m.withLockWaitTimeout(ctx, func(db *gorm.DB) error {
+			gm := gormigrate.New(db, m.options, withTracing(m.withMigrationTimeout(migrations)))
+			if m.initSchema != nil {
+				gm.InitSchema(m.initSchema)
+			}
+
+			if err := gm.Migrate(); err != nil {
+				return err
+			}
+
+			return nil
+		})
+	})
+}
+
+// WithInitSchema - returns a copy of the migration which creates the current schema in one shot
+// on a brand-new database (instead of applying every migration), while existing databases are
+// still migrated incrementally. All the migrations are marked as applied after the init.
+func (m Migration) WithInitSchema(initSchema func(*gorm.DB) error) *Migration {
+	m.initSchema = initSchema
+	return &m
 }
 
 // RollbackLastMigration - rollback last applied migration
 func (m Migration) RollbackLastMigration(
 	migrations []*gormigrate.Migration,
 ) error {
-	gm := gormigrate.New(m.db.DB, m.options, migrations)
+	return m.RollbackLastMigrationContext(context.Background(), migrations)
+}
 
-	if err := gm.RollbackLast(); err != nil {
-		return err
+// RollbackLastMigrationContext - rollback last applied migration, aborted if the context is done
+func (m Migration) RollbackLastMigrationContext(
+	ctx context.Context,
+	migrations []*gormigrate.Migration,
+) error {
+	return m.withAdvisoryLock(ctx, func() error {
+		return m.withLockWaitTimeout(ctx, func(db *gorm.DB) error {
+			gm := gormigrate.New(db, m.options, migrations)
+
+			if err := gm.RollbackLast(); err != nil {
+				return err
+			}
+
+			return nil
+		})
+	})
+}
+
+// RollbackTo - rollback all migrations applied after the given one, in reverse order
+// (within a single transaction if UseTransaction is set)
+func (m Migration) RollbackTo(
+	migrationID string,
+	migrations []*gormigrate.Migration,
+) error {
+	return m.withAdvisoryLock(context.Background(), func() error {
+		applied, err := m.appliedIDs()
+		if err != nil {
+			return err
+		}
+		if !applied[migrationID] {
+			return fmt.Errorf("%w: %s", ErrMigrationNotApplied, migrationID)
+		}
+
+		return m.withLockWaitTimeout(context.Background(), func(db *gorm.DB) error {
+			gm := gormigrate.New(db, m.options, migrations)
+
+			if err := gm.RollbackTo(migrationID); err != nil {
+				return err
+			}
+
+			return nil
+		})
+	})
+}
+
+// RollbackAll - rollback all the applied migrations in reverse order, down to an empty schema
+// (within a single transaction if UseTransaction is set). Nothing is rolled back if any of the
+// applied migrations has no Rollback func, the returned ErrMigrationIrreversible lists these instead.
+func (m Migration) RollbackAll(
+	migrations []*gormigrate.Migration,
+) error {
+	return m.withAdvisoryLock(context.Background(), func() error {
+		applied, err := m.appliedIDs()
+		if err != nil {
+			return err
+		}
+
+		count := 0
+		irreversible := []string{}
+		for _, migration := range migrations {
+			if !applied[migration.ID] {
+				continue
+			}
+			count++
+			if migration.Rollback == nil {
+				irreversible = append(irreversible, migration.ID)
+			}
+		}
+		if len(irreversible) > 0 {
+			return fmt.Errorf("%w: %s", ErrMigrationIrreversible, strings.Join(irreversible, ", "))
+		}
+
+		rollback := func(tx *gorm.DB, options *gormigrate.Options) error {
+			gm := gormigrate.New(tx, options, migrations)
+			for i := 0; i < count; i++ {
+				if err := gm.RollbackLast(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		return m.withLockWaitTimeout(context.Background(), func(db *gorm.DB) error {
+			if !m.options.UseTransaction {
+				return rollback(db, m.options)
+			}
+			// one transaction for all, instead of one per migration
+			options := *m.options
+			options.UseTransaction = false
+			return db.Transaction(func(tx *gorm.DB) error {
+				return rollback(tx, &options)
+			})
+		})
+	})
+}
+
+// Status - report for each of the migrations whether it has been applied, without applying anything
+func (m Migration) Status(
+	migrations []*gormigrate.Migration,
+) ([]MigrationStatus, error) {
+	applied, err := m.appliedIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		statuses = append(statuses, MigrationStatus{
+			ID:      migration.ID,
+			Applied: applied[migration.ID],
+		})
+	}
+	return statuses, nil
+}
+
+// HasPendingMigrations - whether any of the migrations hasn't been applied yet, and the IDs of
+// these (in the order of the migrations), without applying anything
+func (m Migration) HasPendingMigrations(
+	migrations []*gormigrate.Migration,
+) (bool, []string, error) {
+	statuses, err := m.Status(migrations)
+	if err != nil {
+		return false, nil, err
 	}
 
+	pending := []string{}
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.ID)
+		}
+	}
+	return len(pending) > 0, pending, nil
+}
+
+// AssertAllApplied - returns an ErrMigrationNotApplied listing the IDs of the migrations which
+// haven't been applied, like to fail the setup of integration tests when the schema isn't migrated
+func (m Migration) AssertAllApplied(
+	migrations []*gormigrate.Migration,
+) error {
+	pending, ids, err := m.HasPendingMigrations(migrations)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return fmt.Errorf("%w: %s", ErrMigrationNotApplied, strings.Join(ids, ", "))
+	}
 	return nil
 }
 
+// AppliedMigrationIDs - the IDs of the applied migrations, ordered by ID (the migrations table
+// doesn't record when they were applied), or none if the migrations table doesn't exist yet
+func (m Migration) AppliedMigrationIDs() ([]string, error) {
+	ids := []string{}
+	if !m.db.Migrator().HasTable(m.options.TableName) {
+		return ids, nil
+	}
+
+	if err := m.db.
+		Table(m.options.TableName).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: m.options.IDColumnName}}).
+		Pluck(m.options.IDColumnName, &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (m Migration) appliedIDs() (map[string]bool, error) {
+	ids, err := m.AppliedMigrationIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		applied[id] = true
+	}
+	return applied, nil
+}
+
 // WithUseTransaction - add UseTransaction = true to options
 func WithUseTransaction(o *gormigrate.Options) *gormigrate.Options {
 	o.UseTransaction = true
 	return o
 }
+
+// WithTableName - store the applied migrations in the given table instead of "migrations"
+func WithTableName(name string) Option {
+	return func(o *gormigrate.Options) *gormigrate.Options {
+		o.TableName = name
+		return o
+	}
+}
+
+// WithIDColumnName - store the applied migration IDs in the given column instead of "id"
+func WithIDColumnName(name string) Option {
+	return func(o *gormigrate.Options) *gormigrate.Options {
+		o.IDColumnName = name
+		return o
+	}
+}
@@ -0,0 +1,97 @@
+package migration
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/dentech-floss/orm/pkg/orm"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+const upSuffix = ".up.sql"
+const downSuffix = ".down.sql"
+
+// RunSQLMigrations - apply the SQL migrations of the directory (like one embedded via go:embed)
+// that weren't applied before, see LoadSQLMigrations
+func (m Migration) RunSQLMigrations(fsys fs.FS, dir string) error {
+	migrations, err := LoadSQLMigrations(fsys, dir)
+	if err != nil {
+		return err
+	}
+	return m.RunMigrations(migrations)
+}
+
+// LoadSQLMigrations - build a migration per <id>.up.sql file of the directory (like
+// 0001_create_persons.up.sql), ordered by ID, which is rolled back by the <id>.down.sql file (if
+// any). The statements of a file are executed one by one within a transaction, like by
+// orm.ExecSQL. Other files are ignored, so the migrations can be reused for rolling back.
+func LoadSQLMigrations(fsys fs.FS, dir string) ([]*gormigrate.Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ups := map[string]string{}
+	downs := map[string]string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		var scripts map[string]string
+		var id string
+		switch {
+		case strings.HasSuffix(name, upSuffix):
+			scripts, id = ups, strings.TrimSuffix(name, upSuffix)
+		case strings.HasSuffix(name, downSuffix):
+			scripts, id = downs, strings.TrimSuffix(name, downSuffix)
+		default:
+			continue
+		}
+		script, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		scripts[id] = string(script)
+	}
+
+	for id := range downs {
+		if _, ok := ups[id]; !ok {
+			return nil, fmt.Errorf("migration: %s%s has no %s%s", id, downSuffix, id, upSuffix)
+		}
+	}
+
+	ids := make([]string, 0, len(ups))
+	for id := range ups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	migrations := make([]*gormigrate.Migration, 0, len(ids))
+	for _, id := range ids {
+		var rollback func(*gorm.DB) error
+		if down, ok := downs[id]; ok {
+			rollback = execSQLFunc(down)
+		}
+		migrations = append(migrations, NewMigrationStep(id, execSQLFunc(ups[id]), rollback))
+	}
+	return migrations, nil
+}
+
+func execSQLFunc(script string) func(*gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		statements := orm.SplitSQLStatements(script, tx.Dialector.Name())
+		return tx.Transaction(func(tx *gorm.DB) error {
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// NewMigrationStep - create a migration applied by up and rolled back by down, a nil down makes
+// it irreversible (see RollbackAll)
+func NewMigrationStep(id string, up, down func(*gorm.DB) error) *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID:       id,
+		Migrate:  up,
+		Rollback: down,
+	}
+}
+
+// NewReversibleMigrationStep - like NewMigrationStep, but panics when down is nil so a migration
+// which can't be rolled back is caught when the migrations are declared instead of on a rollback
+func NewReversibleMigrationStep(id string, up, down func(*gorm.DB) error) *gormigrate.Migration {
+	if down == nil {
+		panic(fmt.Errorf("%w: %s has no rollback", ErrMigrationIrreversible, id))
+	}
+	return NewMigrationStep(id, up, down)
+}
@@ -0,0 +1,50 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// ErrMigrationTimeout - a migration didn't complete within the migration timeout
+var ErrMigrationTimeout = errors.New("migration: migration timed out")
+
+// WithMigrationTimeout - returns a copy of the migration which aborts each migration that runs longer
+// than the timeout, by cancelling the context of its statements, and returns an ErrMigrationTimeout
+// error identifying it. This protects against a runaway migration blocking the whole deploy.
+func (m Migration) WithMigrationTimeout(timeout time.Duration) *Migration {
+	m.migrationTimeout = timeout
+	return &m
+}
+
+// Wrap the Migrate func of each migration with the timeout, the given migrations are not modified
+func (m Migration) withMigrationTimeout(
+	migrations []*gormigrate.Migration,
+) []*gormigrate.Migration {
+	if m.migrationTimeout <= 0 {
+		return migrations
+	}
+
+	wrapped := make([]*gormigrate.Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		migration := *migration
+		if migrate := migration.Migrate; migrate != nil {
+			migration.Migrate = func(tx *gorm.DB) error {
+				ctx, cancel := context.WithTimeout(tx.Statement.Context, m.migrationTimeout)
+				defer cancel()
+
+				err := migrate(tx.WithContext(ctx))
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return fmt.Errorf("%w after %s: %s", ErrMigrationTimeout, m.migrationTimeout, migration.ID)
+				}
+				return err
+			}
+		}
+		wrapped = append(wrapped, &migration)
+	}
+	return wrapped
+}
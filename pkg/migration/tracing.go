@@ -0,0 +1,68 @@
+package migration
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const instrumentationName = "github.com/dentech-floss/orm/pkg/migration"
+
+// Start the aggregate "db.migrate" span, the spans of the applied migrations are its children
+func startMigrateSpan(ctx context.Context) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, "db.migrate")
+}
+
+// End the span, recording the error (if any) as its outcome
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Wrap the Migrate func of each migration with a "db.migration" span and a duration metric
+// ("db.migration.duration", in seconds), both having the migration ID and the outcome. The
+// global tracer/meter providers are used, so this is a no-op unless Opentelemetry is set up.
+func withTracing(migrations []*gormigrate.Migration) []*gormigrate.Migration {
+	tracer := otel.Tracer(instrumentationName)
+	duration, err := otel.Meter(instrumentationName).Float64Histogram(
+		"db.migration.duration",
+		metric.WithDescription("The duration of applying a migration"),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	wrapped := make([]*gormigrate.Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		migration := *migration
+		if migrate := migration.Migrate; migrate != nil {
+			migration.Migrate = func(tx *gorm.DB) error {
+				ctx, span := tracer.Start(tx.Statement.Context, "db.migration",
+					trace.WithAttributes(attribute.String("db.migration.id", migration.ID)))
+				start := time.Now()
+
+				err := migrate(tx.WithContext(ctx))
+
+				if duration != nil {
+					duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+						attribute.String("db.migration.id", migration.ID),
+						attribute.Bool("db.migration.success", err == nil)))
+				}
+				endSpan(span, err)
+				return err
+			}
+		}
+		wrapped = append(wrapped, &migration)
+	}
+	return wrapped
+}
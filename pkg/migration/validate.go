@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+)
+
+// ErrInvalidMigrationID - a migration has an empty ID, or the same ID as another one
+var ErrInvalidMigrationID = errors.New("migration: invalid migration ID")
+
+// Check the IDs up front, gormigrate only notices an empty ID once the preceding migrations
+// have been applied. All the duplicated IDs are reported at once.
+func validateMigrationIDs(migrations []*gormigrate.Migration) error {
+	seen := make(map[string]bool, len(migrations))
+	duplicated := []string{}
+	for i, migration := range migrations {
+		if migration.ID == "" {
+			return fmt.Errorf("%w: migration %d has no ID", ErrInvalidMigrationID, i)
+		}
+		if seen[migration.ID] {
+			duplicated = append(duplicated, migration.ID)
+		}
+		seen[migration.ID] = true
+	}
+	if len(duplicated) > 0 {
+		return fmt.Errorf("%w: duplicated %s", ErrInvalidMigrationID, strings.Join(duplicated, ", "))
+	}
+	return nil
+}
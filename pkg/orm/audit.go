@@ -0,0 +1,93 @@
+package orm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var auditCreatedByColumn = "created_by"
+var auditUpdatedByColumn = "updated_by"
+
+type auditActorKey struct{}
+
+// WithAuditContext - stash the actor (like a user id) in the context, which is then written by
+// an Audit enabled Orm to the created_by/updated_by columns of the models having them
+func WithAuditContext(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+func auditActor(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(auditActorKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// Register the callbacks filling the audit columns, a soft delete is an update of the row so it
+// sets updated_by as well (while a hard delete leaves nothing to record it in)
+func registerAudit(db *gorm.DB) error {
+	if callbacksRegistered(db, "orm:audit") {
+		return nil
+	}
+
+	callback := db.Callback()
+	if err := callback.Create().Before("gorm:create").Register("orm:audit", auditCreate); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("orm:audit", auditUpdate); err != nil {
+		return err
+	}
+	return callback.Delete().Before("gorm:delete").Register("orm:audit", auditSoftDelete)
+}
+
+func auditCreate(tx *gorm.DB) {
+	actor, ok := auditActor(tx.Statement.Context)
+	if !ok {
+		return
+	}
+	setAuditColumn(tx, auditCreatedByColumn, actor)
+	setAuditColumn(tx, auditUpdatedByColumn, actor)
+}
+
+func auditUpdate(tx *gorm.DB) {
+	if actor, ok := auditActor(tx.Statement.Context); ok {
+		setAuditColumn(tx, auditUpdatedByColumn, actor)
+	}
+}
+
+func setAuditColumn(tx *gorm.DB, column string, actor string) {
+	if tx.Statement.Schema != nil && tx.Statement.Schema.LookUpField(column) != nil {
+		tx.Statement.SetColumn(column, actor, true)
+	}
+}
+
+// The soft delete clause builds the UPDATE statement by itself with deleted_at as the only
+// assignment (replacing any SET clause added before), so append updated_by after it instead
+func auditSoftDelete(tx *gorm.DB) {
+	stmt := tx.Statement
+	if stmt.Unscoped || stmt.Schema == nil || stmt.SQL.Len() > 0 {
+		return
+	}
+	actor, ok := auditActor(stmt.Context)
+	if !ok {
+		return
+	}
+	field := stmt.Schema.LookUpField(auditUpdatedByColumn)
+	if field == nil || !isSoftDeletable(stmt) {
+		return
+	}
+
+	set := stmt.Clauses["SET"]
+	set.Name = "SET"
+	set.AfterExpression = clause.Expr{SQL: ", ? = ?", Vars: []interface{}{clause.Column{Name: field.DBName}, actor}}
+	stmt.Clauses["SET"] = set
+}
+
+func isSoftDeletable(stmt *gorm.Statement) bool {
+	for _, c := range stmt.Schema.DeleteClauses {
+		if _, ok := c.(gorm.SoftDeleteDeleteClause); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package orm
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// AuthTokenProvider - provides a (short-lived) password, like an AWS RDS IAM auth token.
+//
+// The token is only used to authenticate when a new physical connection is established, an
+// already open connection stays valid after the token has expired. So ConnMaxLifetimeMins
+// doesn't have to be lower than the token lifetime, but keep it low enough (it defaults to 5)
+// to make sure that connections are recycled and re-authenticated regularly.
+type AuthTokenProvider func(ctx context.Context) (string, error)
+
+// Connector that fetches a fresh token as password for each new MySQL connection
+type mysqlTokenConnector struct {
+	dsn           string
+	tokenProvider AuthTokenProvider
+}
+
+func (c *mysqlTokenConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	cfg, err := mysql.ParseDSN(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Passwd, err = c.tokenProvider(ctx); err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(ctx)
+}
+
+func (c *mysqlTokenConnector) Driver() driver.Driver {
+	return &mysql.MySQLDriver{}
+}
+
+// Connector that fetches a fresh token as password for each new Postgres connection
+type postgresTokenConnector struct {
+	dsn           string
+	tokenProvider AuthTokenProvider
+}
+
+func (c *postgresTokenConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	cfg, err := pgx.ParseConfig(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Password, err = c.tokenProvider(ctx); err != nil {
+		return nil, err
+	}
+	return stdlib.GetConnector(*cfg).Connect(ctx)
+}
+
+func (c *postgresTokenConnector) Driver() driver.Driver {
+	return stdlib.GetDefaultDriver()
+}
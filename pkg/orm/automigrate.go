@@ -0,0 +1,159 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrDropAllTablesNotAllowed - DropAllTables was called without AllowDropAllTables being set
+var ErrDropAllTablesNotAllowed = errors.New("orm: dropping all tables is not allowed, set AllowDropAllTables to enable it")
+
+// AutoMigrateModels - auto migrate the models with the foreign key checks disabled (MySQL and SQLite),
+// so the order of the models doesn't matter even if they depend on each other
+func (db *Orm) AutoMigrateModels(models ...interface{}) error {
+	// the foreign key checks are per session, so everything must be done on the same connection
+	return db.DB.Connection(func(tx *gorm.DB) error {
+		restore, err := disableForeignKeyChecks(tx)
+		if err != nil {
+			return err
+		}
+		defer restore()
+
+		for _, model := range models {
+			if err := tx.AutoMigrate(model); err != nil {
+				return fmt.Errorf("orm: failed to migrate %T: %w", model, err)
+			}
+		}
+		return nil
+	})
+}
+
+// PendingSchemaChanges - compare the models with the live schema, without changing anything, and
+// describe the differences AutoMigrate would act on: missing tables and columns, and columns of
+// another type (like for a CI check that no migration was forgotten). Empty when there are none.
+func (db *Orm) PendingSchemaChanges(models ...interface{}) ([]string, error) {
+	changes := []string{}
+	migrator := db.DB.Migrator()
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db.DB}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("orm: failed to parse %T: %w", model, err)
+		}
+		if !migrator.HasTable(model) {
+			changes = append(changes, fmt.Sprintf("table %s is missing", stmt.Table))
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(model)
+		if err != nil {
+			return nil, fmt.Errorf("orm: failed to get the columns of %s: %w", stmt.Table, err)
+		}
+		columns := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, columnType := range columnTypes {
+			columns[strings.ToLower(columnType.Name())] = columnType
+		}
+
+		for _, dbName := range stmt.Schema.DBNames {
+			field := stmt.Schema.LookUpField(dbName)
+			if field.IgnoreMigration {
+				continue
+			}
+			columnType, ok := columns[strings.ToLower(dbName)]
+			if !ok {
+				changes = append(changes, fmt.Sprintf("column %s.%s is missing", stmt.Table, dbName))
+				continue
+			}
+			if expected := migrator.FullDataTypeOf(field).SQL; !sameColumnType(migrator, field, columnType, expected) {
+				actual, ok := columnType.ColumnType()
+				if !ok {
+					actual = columnType.DatabaseTypeName()
+				}
+				changes = append(changes, fmt.Sprintf("column %s.%s is %s instead of %s", stmt.Table, dbName, strings.ToLower(actual), strings.ToLower(expected)))
+			}
+		}
+	}
+	return changes, nil
+}
+
+// The type (and size) checks of gorm's MigrateColumn, which alters the column when they differ
+func sameColumnType(migrator gorm.Migrator, field *schema.Field, columnType gorm.ColumnType, expected string) bool {
+	fullDataType := strings.TrimSpace(strings.ToLower(expected))
+	realDataType := strings.ToLower(columnType.DatabaseTypeName())
+	if fullDataType == realDataType {
+		return true
+	}
+
+	if !field.PrimaryKey && !strings.HasPrefix(fullDataType, realDataType) {
+		aliased := false
+		for _, alias := range migrator.GetTypeAliases(realDataType) {
+			if strings.HasPrefix(fullDataType, alias) {
+				aliased = true
+				break
+			}
+		}
+		if !aliased {
+			return false
+		}
+	}
+
+	length, _ := columnType.Length()
+	return length <= 0 || field.Size <= 0 || length == int64(field.Size)
+}
+
+// DropAllTables - drop all tables (with the foreign key checks disabled) to reset a test database,
+// refuses to run unless AllowDropAllTables is set to avoid accidentally wiping a real database
+func (db *Orm) DropAllTables() error {
+	if !db.config.AllowDropAllTables {
+		return ErrDropAllTablesNotAllowed
+	}
+
+	return db.DB.Connection(func(tx *gorm.DB) error {
+		restore, err := disableForeignKeyChecks(tx)
+		if err != nil {
+			return err
+		}
+		defer restore()
+
+		tables, err := tx.Migrator().GetTables()
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			if tx.Dialector.Name() == "sqlite" && strings.HasPrefix(table, "sqlite_") {
+				continue // internal tables, like sqlite_sequence, can't be dropped
+			}
+			if err := tx.Migrator().DropTable(table); err != nil {
+				return fmt.Errorf("orm: failed to drop table %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Disable the foreign key checks for the session, returning a func restoring the previous setting
+func disableForeignKeyChecks(tx *gorm.DB) (func(), error) {
+	var getSQL, setSQL string
+	switch tx.Dialector.Name() {
+	case "mysql":
+		getSQL, setSQL = "SELECT @@SESSION.foreign_key_checks", "SET SESSION foreign_key_checks = %d"
+	case "sqlite":
+		getSQL, setSQL = "PRAGMA foreign_keys", "PRAGMA foreign_keys = %d"
+	default:
+		return func() {}, nil
+	}
+
+	var enabled int
+	if err := tx.Raw(getSQL).Scan(&enabled).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Exec(fmt.Sprintf(setSQL, 0)).Error; err != nil {
+		return nil, err
+	}
+	return func() {
+		tx.Exec(fmt.Sprintf(setSQL, enabled))
+	}, nil
+}
@@ -0,0 +1,87 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+var defaultBatchSize = 1000
+
+// CreateInBatchesCtx - insert the value (a slice) in batches of batchSize rows (defaults to 1000
+// when zero or negative), which keeps each statement within the packet/parameter limits of the
+// database. The context applies to all the batches which are created within a single transaction
+// (unless SkipDefaultTransaction is set).
+func (db *Orm) CreateInBatchesCtx(ctx context.Context, value interface{}, batchSize int) error {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return db.DB.WithContext(ctx).CreateInBatches(value, batchSize).Error
+}
+
+// EachInBatches - iterate over the rows of the model (a pointer to a slice, also receiving each
+// batch) batchSize rows at a time (defaults to 1000 when zero or negative), ordered by primary key.
+// The iteration stops at the first error returned by fn or once the context is done.
+func (db *Orm) EachInBatches(ctx context.Context, model interface{}, batchSize int, fn func(batch interface{}) error) error {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return db.DB.WithContext(ctx).FindInBatches(model, batchSize, func(_ *gorm.DB, _ int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(model)
+	}).Error
+}
+
+// DeleteInBatches - delete the rows of the model matching the conds (like a map or a clause.Expression)
+// batchSize rows at a time (defaults to 1000 when zero or negative) until none remain, returning
+// the number of deleted rows. Each batch is a statement of its own, so the locks are released in
+// between. The keys are selected on the primary (not ReplicaHosts, which may lag behind the deletes),
+// and it stops once a batch deletes no rows (like when they were deleted concurrently). The model
+// must have a single primary key, and conds are required to not purge the table.
+func (db *Orm) DeleteInBatches(ctx context.Context, model interface{}, conds interface{}, batchSize int) (int64, error) {
+	if conds == nil {
+		return 0, gorm.ErrMissingWhereClause
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	stmt := &gorm.Statement{DB: db.DB}
+	if err := stmt.Parse(model); err != nil {
+		return 0, err
+	}
+	if len(stmt.Schema.PrimaryFields) != 1 {
+		return 0, fmt.Errorf("orm: DeleteInBatches requires a single primary key, %s has %d", stmt.Schema.Name, len(stmt.Schema.PrimaryFields))
+	}
+	primaryKey := stmt.Schema.PrimaryFields[0].DBName
+
+	// select the keys of the batch first since not all databases support DELETE ... LIMIT
+	var deleted int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+
+		var keys []interface{}
+		if err := db.DB.WithContext(ctx).Clauses(dbresolver.Write).Model(model).Where(conds).Limit(batchSize).Pluck(primaryKey, &keys).Error; err != nil {
+			return deleted, err
+		}
+		if len(keys) == 0 {
+			return deleted, nil
+		}
+
+		result := db.DB.WithContext(ctx).Where(clause.IN{Column: clause.Column{Name: primaryKey}, Values: keys}).Delete(model)
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return deleted, nil
+		}
+		deleted += result.RowsAffected
+	}
+}
@@ -0,0 +1,31 @@
+package orm
+
+import (
+	"errors"
+	"maps"
+	"slices"
+)
+
+// ErrCloneNotSupported - Clone was called on an Orm created by NewOrm, the dialector of which can't be rebuilt
+var ErrCloneNotSupported = errors.New("orm: Clone is not supported for an Orm created by NewOrm")
+
+// Clone - open a new connection with a copy of the config (like for another tenant database)
+// having the overrides applied, using the same constructor as for this Orm. The values defaulted
+// by the constructor are part of the copy, and the Orm being cloned is left as it is.
+func (db *Orm) Clone(overrides func(config *OrmConfig)) (*Orm, error) {
+	if db.config.constructor == nil {
+		return nil, ErrCloneNotSupported
+	}
+
+	db.config.poolMu.Lock()
+	config := *db.config
+	db.config.poolMu.Unlock()
+	config.ReplicaHosts = slices.Clone(config.ReplicaHosts)
+	config.DSNParams = maps.Clone(config.DSNParams)
+	config.SessionVariables = maps.Clone(config.SessionVariables)
+	if overrides != nil {
+		overrides(&config)
+	}
+
+	return config.constructor(&config)
+}
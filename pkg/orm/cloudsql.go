@@ -0,0 +1,43 @@
+package orm
+
+import (
+	"fmt"
+	"sync"
+
+	"cloud.google.com/go/cloudsqlconn"
+	cloudsqlmysql "cloud.google.com/go/cloudsqlconn/mysql/mysql"
+	cloudsqlpostgres "cloud.google.com/go/cloudsqlconn/postgres/pgxv5"
+)
+
+const cloudSQLMySQLDriver = "cloudsql-mysql"
+const cloudSQLPostgresDriver = "cloudsql-postgres"
+
+// The drivers are registered globally so this can only be done once per process, the
+// dialers are then kept open for the lifetime of the process.
+var registerCloudSQLMySQLDriver = sync.OnceValue(func() error {
+	_, err := cloudsqlmysql.RegisterDriver(cloudSQLMySQLDriver, cloudsqlconn.WithIAMAuthN())
+	return err
+})
+
+var registerCloudSQLPostgresDriver = sync.OnceValue(func() error {
+	_, err := cloudsqlpostgres.RegisterDriver(cloudSQLPostgresDriver, cloudsqlconn.WithIAMAuthN())
+	return err
+})
+
+// Create MySQL DB connection string for the Cloud SQL Go Connector, which takes care of the
+// host and the IAM authentication so neither host/port nor password is part of it
+func cloudSQLMySQLDsn(config *OrmConfig) string {
+	params := mysqlParams(config)
+	return fmt.Sprintf(
+		"%s@%s(%s)/%s?%s",
+		config.DbUser, cloudSQLMySQLDriver, config.InstanceConnectionName, config.DbName, dsnQuery(params, config))
+}
+
+// Create Postgres DB connection string for the Cloud SQL Go Connector, the connector
+// encrypts the connection itself so SSL must be disabled in the driver
+func cloudSQLPostgresDsn(config *OrmConfig) string {
+	return fmt.Sprintf(
+		"host=%s user=%s dbname=%s sslmode=disable connect_timeout=%d",
+		quote(config.InstanceConnectionName), quote(config.DbUser), quote(config.DbName),
+		connectTimeoutSeconds(config)) + postgresSessionVariables(config)
+}
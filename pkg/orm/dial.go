@@ -0,0 +1,39 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// DialFunc - dials the database address (host:port), like through an SSH tunnel or a mesh sidecar
+type DialFunc func(ctx context.Context, addr string) (net.Conn, error)
+
+var dialNetworkSeq atomic.Uint64
+
+// The MySQL driver looks up custom dial functions by the network name of the DSN in a process wide
+// registry, so each Orm registers its dial function under a name of its own (shared by its replicas)
+func registerDialNetwork(config *OrmConfig) {
+	if config.DialFunc == nil {
+		return
+	}
+	config.dialNetwork = fmt.Sprintf("orm-dial-%d", dialNetworkSeq.Add(1))
+	mysql.RegisterDialContext(config.dialNetwork, mysql.DialContextFunc(config.DialFunc))
+}
+
+func deregisterDialNetwork(config *OrmConfig) {
+	if config.dialNetwork != "" {
+		mysql.DeregisterDialContext(config.dialNetwork)
+	}
+}
+
+// The network of the MySQL TCP DSN, the custom dial function when there is one
+func mysqlNetwork(config *OrmConfig) string {
+	if config.dialNetwork != "" {
+		return config.dialNetwork
+	}
+	return "tcp"
+}
@@ -0,0 +1,105 @@
+package orm
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// OrmConfigFromEnv - creates a config from the DB_ prefixed env variables, see OrmConfigFromEnvWithPrefix
+func OrmConfigFromEnv() (*OrmConfig, error) {
+	return OrmConfigFromEnvWithPrefix("DB_")
+}
+
+// OrmConfigFromEnvWithPrefix - creates a config from env variables with the given prefix (like "DB_"):
+//
+//	<prefix>NAME, <prefix>USER, <prefix>HOST        required (HOST not if USE_CLOUD_SQL_CONNECTOR or GCP_PROXY_TCP is set)
+//	<prefix>PASSWORD, <prefix>PORT, <prefix>SSL_MODE
+//	<prefix>ON_GCP, <prefix>GCP_PROXY_TCP, <prefix>USE_CLOUD_SQL_CONNECTOR bool, like "true" or "1"
+//	<prefix>INSTANCE_CONNECTION_NAME                required if USE_CLOUD_SQL_CONNECTOR is set
+//	<prefix>MAX_IDLE_CONNS, <prefix>MAX_OPEN_CONNS, <prefix>CONN_MAX_LIFETIME_MINS, <prefix>CONN_MAX_IDLE_TIME_MINS
+//	<prefix>CONNECT_TIMEOUT                         duration, like "10s"
+//
+// Unset optional variables fall back to the same defaults as when not set in the config.
+func OrmConfigFromEnvWithPrefix(prefix string) (*OrmConfig, error) {
+	env := envReader{prefix: prefix}
+
+	config := &OrmConfig{
+		DbName:                 env.string("NAME"),
+		DbUser:                 env.string("USER"),
+		DbPassword:             env.string("PASSWORD"),
+		DbHost:                 env.string("HOST"),
+		SSLMode:                env.string("SSL_MODE"),
+		InstanceConnectionName: env.string("INSTANCE_CONNECTION_NAME"),
+		OnGCP:                  env.bool("ON_GCP"),
+		GCPProxyTCP:            env.bool("GCP_PROXY_TCP"),
+		UseCloudSQLConnector:   env.bool("USE_CLOUD_SQL_CONNECTOR"),
+		DbPort:                 env.int("PORT"),
+		MaxIdleConns:           env.int("MAX_IDLE_CONNS"),
+		MaxOpenConns:           env.int("MAX_OPEN_CONNS"),
+		ConnMaxLifetimeMins:    env.int("CONN_MAX_LIFETIME_MINS"),
+		ConnMaxIdleTimeMins:    env.int("CONN_MAX_IDLE_TIME_MINS"),
+		ConnectTimeout:         env.duration("CONNECT_TIMEOUT"),
+	}
+	if env.err != nil {
+		return nil, env.err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Reads the env variables, remembering the first parse error
+type envReader struct {
+	prefix string
+	err    error
+}
+
+func (r *envReader) string(name string) string {
+	return os.Getenv(r.prefix + name)
+}
+
+func (r *envReader) bool(name string) bool {
+	value, isSet := r.lookup(name)
+	if !isSet {
+		return false
+	}
+	b, err := strconv.ParseBool(value)
+	r.setErr(name, err)
+	return b
+}
+
+func (r *envReader) int(name string) *int {
+	value, isSet := r.lookup(name)
+	if !isSet {
+		return nil
+	}
+	i, err := strconv.Atoi(value)
+	r.setErr(name, err)
+	return &i
+}
+
+func (r *envReader) duration(name string) *time.Duration {
+	value, isSet := r.lookup(name)
+	if !isSet {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	r.setErr(name, err)
+	return &d
+}
+
+func (r *envReader) lookup(name string) (string, bool) {
+	value, isSet := os.LookupEnv(r.prefix + name)
+	return value, isSet && value != ""
+}
+
+func (r *envReader) setErr(name string, err error) {
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("%w: %s%s: %v", ErrInvalidConfig, r.prefix, name, err)
+	}
+}
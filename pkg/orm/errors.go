@@ -0,0 +1,21 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The causes of a failing constructor, which wrap the underlying error so both can be matched
+// with errors.Is/As. A connect error might be worth retrying while the others won't go away.
+var (
+	// ErrConnect - the connection to the database couldn't be established
+	ErrConnect = errors.New("orm: failed to connect")
+	// ErrPluginRegistration - a plugin or callback (tracing, replicas, metrics...) failed to register
+	ErrPluginRegistration = errors.New("orm: failed to register plugin")
+	// ErrPoolSetup - the underlying connection pool couldn't be configured
+	ErrPoolSetup = errors.New("orm: failed to set up the connection pool")
+)
+
+func wrapErr(cause error, err error) error {
+	return fmt.Errorf("%w: %w", cause, err)
+}
@@ -0,0 +1,84 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HealthEvent - the outcome of a health check, Err is set when it's unhealthy
+type HealthEvent struct {
+	Time    time.Time
+	Healthy bool
+	Err     error
+}
+
+// StartHealthCheck - periodically ping the database until the context is done. When a ping
+// fails the idle connections are dropped so the pool re-establishes fresh ones.
+// An event is sent for the first check and then whenever the health changes, and the
+// channel is closed when the context is done.
+func (db *Orm) StartHealthCheck(ctx context.Context, interval time.Duration) <-chan HealthEvent {
+	events := make(chan HealthEvent, 1)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last *HealthEvent
+		for {
+			event := db.checkHealth(ctx, interval)
+			if ctx.Err() != nil {
+				return
+			}
+			if last == nil || last.Healthy != event.Healthy {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			last = &event
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+func (db *Orm) checkHealth(ctx context.Context, timeout time.Duration) HealthEvent {
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := db.Ping(pingCtx)
+	if err != nil {
+		_ = db.ResetPool()
+	}
+	return HealthEvent{Time: time.Now(), Healthy: err == nil, Err: err}
+}
+
+// ResetPool - close the idle connections (like the ones to the old primary after a failover) so
+// the pool establishes new ones when next needed, a lighter alternative to reconnecting. The
+// connections in use are kept, they are closed when expiring by ConnMaxLifetimeMins as usual.
+func (db *Orm) ResetPool() error {
+	if db.config.externalPool {
+		return fmt.Errorf("%w: the pool given to NewOrmFromSQLDB can't be reset", ErrPoolSetup)
+	}
+	sqlDB, err := db.SQLDB()
+	if err != nil {
+		return err
+	}
+	// there is no way to close the idle connections directly, so temporarily don't allow any,
+	// locked so a concurrent SetMaxIdleConns isn't overwritten by the limit restored
+	db.config.poolMu.Lock()
+	defer db.config.poolMu.Unlock()
+	sqlDB.SetMaxIdleConns(0)
+	sqlDB.SetMaxIdleConns(*db.config.MaxIdleConns)
+	return nil
+}
@@ -0,0 +1,173 @@
+package orm
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OrmConfigFromJSON - creates a config from JSON (like a mounted secret), see OrmConfig.UnmarshalJSON
+func OrmConfigFromJSON(data []byte) (*OrmConfig, error) {
+	config := &OrmConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// The JSON representation of the config, with plain values where zero means the default
+type jsonConfig struct {
+	OnGCP                                    bool              `json:"on_gcp"`
+	GCPProxyTCP                              bool              `json:"gcp_proxy_tcp"`
+	UseCloudSQLConnector                     bool              `json:"use_cloud_sql_connector"`
+	InstanceConnectionName                   string            `json:"instance_connection_name"`
+	DbName                                   string            `json:"db_name"`
+	DbUser                                   string            `json:"db_user"`
+	DbPassword                               string            `json:"db_password"`
+	DbHost                                   string            `json:"db_host"`
+	DbPort                                   int               `json:"db_port"`
+	ReplicaHosts                             []string          `json:"replica_hosts"`
+	MariaDB                                  bool              `json:"mariadb"`
+	Charset                                  string            `json:"charset"`
+	Collation                                string            `json:"collation"`
+	DefaultStringSize                        uint              `json:"default_string_size"`
+	DefaultDatetimePrecision                 *int              `json:"default_datetime_precision"`
+	DisableDatetimePrecision                 bool              `json:"disable_datetime_precision"`
+	DontSupportRenameIndex                   bool              `json:"dont_support_rename_index"`
+	DontSupportRenameColumn                  bool              `json:"dont_support_rename_column"`
+	DontSupportNullAsDefaultValue            bool              `json:"dont_support_null_as_default_value"`
+	SkipInitializeWithVersion                bool              `json:"skip_initialize_with_version"`
+	Location                                 string            `json:"location"`
+	SSLMode                                  string            `json:"ssl_mode"`
+	SearchPath                               string            `json:"search_path"`
+	ApplicationName                          string            `json:"application_name"`
+	SQLiteDSN                                string            `json:"sqlite_dsn"`
+	DSNParams                                map[string]string `json:"dsn_params"`
+	SessionVariables                         map[string]string `json:"session_variables"`
+	RawDSN                                   string            `json:"raw_dsn"`
+	MaxIdleConns                             int               `json:"max_idle_conns"`
+	MaxOpenConns                             int               `json:"max_open_conns"`
+	ConnMaxLifetimeMins                      int               `json:"conn_max_lifetime_mins"`
+	ConnMaxIdleTimeMins                      int               `json:"conn_max_idle_time_mins"`
+	ConnectTimeout                           string            `json:"connect_timeout"`
+	MinServerVersion                         string            `json:"min_server_version"`
+	DefaultQueryTimeout                      string            `json:"default_query_timeout"`
+	SlowQueryThreshold                       string            `json:"slow_query_threshold"`
+	SlowQueryLogsPerSecond                   int               `json:"slow_query_logs_per_second"`
+	SlowTransactionThreshold                 string            `json:"slow_transaction_threshold"`
+	ReadOnly                                 bool              `json:"read_only"`
+	PrepareStmt                              bool              `json:"prepare_stmt"`
+	SkipDefaultTransaction                   bool              `json:"skip_default_transaction"`
+	DisableAutomaticPing                     bool              `json:"disable_automatic_ping"`
+	TablePrefix                              string            `json:"table_prefix"`
+	SingularTable                            bool              `json:"singular_table"`
+	DisableForeignKeyConstraintWhenMigrating bool              `json:"disable_foreign_key_constraint_when_migrating"`
+	UTCTimestamps                            bool              `json:"utc_timestamps"`
+	Audit                                    bool              `json:"audit"`
+	DisableTracing                           bool              `json:"disable_tracing"`
+}
+
+// UnmarshalJSON - sets the config from snake_cased keys like "db_name", "max_idle_conns" and
+// "connect_timeout" (a duration like "10s"), where zero or missing values fall back to the same
+// defaults as when not set in the config. The fields which can't be expressed in JSON (like the
+// Logger) are left as they are. YAML can be used by converting it to JSON, like sigs.k8s.io/yaml does.
+func (c *OrmConfig) UnmarshalJSON(data []byte) error {
+	var j jsonConfig
+	if err := json.Unmarshal(data, &j); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	c.OnGCP = j.OnGCP
+	c.GCPProxyTCP = j.GCPProxyTCP
+	c.UseCloudSQLConnector = j.UseCloudSQLConnector
+	c.InstanceConnectionName = j.InstanceConnectionName
+	c.DbName = j.DbName
+	c.DbUser = j.DbUser
+	c.DbPassword = j.DbPassword
+	c.DbHost = j.DbHost
+	c.DbPort = nonZeroInt(j.DbPort)
+	c.ReplicaHosts = j.ReplicaHosts
+	c.MariaDB = j.MariaDB
+	c.Charset = j.Charset
+	c.Collation = j.Collation
+	c.DefaultStringSize = j.DefaultStringSize
+	c.DefaultDatetimePrecision = j.DefaultDatetimePrecision
+	c.DisableDatetimePrecision = j.DisableDatetimePrecision
+	c.DontSupportRenameIndex = j.DontSupportRenameIndex
+	c.DontSupportRenameColumn = j.DontSupportRenameColumn
+	c.DontSupportNullAsDefaultValue = j.DontSupportNullAsDefaultValue
+	c.SkipInitializeWithVersion = j.SkipInitializeWithVersion
+	c.SSLMode = j.SSLMode
+	c.SearchPath = j.SearchPath
+	c.ApplicationName = j.ApplicationName
+	c.SQLiteDSN = j.SQLiteDSN
+	c.DSNParams = j.DSNParams
+	c.SessionVariables = j.SessionVariables
+	c.RawDSN = j.RawDSN
+	c.MaxIdleConns = nonZeroInt(j.MaxIdleConns)
+	c.MaxOpenConns = nonZeroInt(j.MaxOpenConns)
+	c.ConnMaxLifetimeMins = nonZeroInt(j.ConnMaxLifetimeMins)
+	c.ConnMaxIdleTimeMins = nonZeroInt(j.ConnMaxIdleTimeMins)
+	c.MinServerVersion = j.MinServerVersion
+	c.ReadOnly = j.ReadOnly
+	c.PrepareStmt = j.PrepareStmt
+	c.SkipDefaultTransaction = j.SkipDefaultTransaction
+	c.DisableAutomaticPing = j.DisableAutomaticPing
+	c.TablePrefix = j.TablePrefix
+	c.SingularTable = j.SingularTable
+	c.DisableForeignKeyConstraintWhenMigrating = j.DisableForeignKeyConstraintWhenMigrating
+	c.UTCTimestamps = j.UTCTimestamps
+	c.Audit = j.Audit
+	c.DisableTracing = j.DisableTracing
+	c.SlowQueryLogsPerSecond = j.SlowQueryLogsPerSecond
+
+	c.Location = nil
+	if j.Location != "" {
+		location, err := time.LoadLocation(j.Location)
+		if err != nil {
+			return fmt.Errorf("%w: location: %v", ErrInvalidConfig, err)
+		}
+		c.Location = location
+	}
+
+	var err error
+	if c.ConnectTimeout, err = nonZeroDuration("connect_timeout", j.ConnectTimeout); err != nil {
+		return err
+	}
+	if c.DefaultQueryTimeout, err = nonZeroDuration("default_query_timeout", j.DefaultQueryTimeout); err != nil {
+		return err
+	}
+	if c.SlowQueryThreshold, err = nonZeroDuration("slow_query_threshold", j.SlowQueryThreshold); err != nil {
+		return err
+	}
+	if c.SlowTransactionThreshold, err = nonZeroDuration("slow_transaction_threshold", j.SlowTransactionThreshold); err != nil {
+		return err
+	}
+	return nil
+}
+
+func nonZeroInt(i int) *int {
+	if i == 0 {
+		return nil
+	}
+	return &i
+}
+
+func nonZeroDuration(name string, value string) (*time.Duration, error) {
+	if value == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidConfig, name, err)
+	}
+	if d == 0 {
+		return nil, nil
+	}
+	return &d, nil
+}
@@ -0,0 +1,91 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrOrmAlreadyRegistered - an Orm with the same name is already registered with the OrmManager
+var ErrOrmAlreadyRegistered = errors.New("orm: an Orm with the name is already registered")
+
+// OrmManager - holds the named Orm objects of a service talking to several databases (like main and
+// analytics), so they can be injected as one and closed together on shutdown
+type OrmManager struct {
+	mu      sync.RWMutex
+	orms    map[string]*Orm
+	pending map[string]bool // the names reserved by the Register calls still creating their Orm
+}
+
+// NewOrmManager - creates an OrmManager without any Orm
+func NewOrmManager() *OrmManager {
+	return &OrmManager{orms: map[string]*Orm{}, pending: map[string]bool{}}
+}
+
+// Register - creates an Orm with the constructor (like NewMySqlOrmWithError) and registers it
+// under the name, which must not be taken already. The Orm is created without holding the lock
+// (which may take a while with ConnectRetry), the name is reserved meanwhile.
+func (m *OrmManager) Register(
+	name string,
+	config *OrmConfig,
+	constructor func(config *OrmConfig, options ...Option) (*Orm, error),
+	options ...Option,
+) (*Orm, error) {
+	m.mu.Lock()
+	if _, registered := m.orms[name]; registered || m.pending[name] {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrOrmAlreadyRegistered, name)
+	}
+	m.pending[name] = true
+	m.mu.Unlock()
+
+	orm, err := constructor(config, options...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pending, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	m.orms[name] = orm
+	return orm, nil
+}
+
+// Get - the Orm registered under the name, false if there is none
+func (m *OrmManager) Get(name string) (*Orm, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	orm, registered := m.orms[name]
+	return orm, registered
+}
+
+// Names - the names of the registered Orm objects, sorted
+func (m *OrmManager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.orms))
+	for name := range m.orms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CloseAll - closes all the registered Orm objects (even if closing one of them fails) and
+// unregisters them, returning the errors joined
+func (m *OrmManager) CloseAll() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	errs := []error{}
+	for name, orm := range m.orms {
+		if err := orm.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	m.orms = map[string]*Orm{}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+package orm
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// Option - type for function for config change
+type Option func(*OrmConfig) *OrmConfig
+
+// WithLogger - use the given logger, takes precedence over WithSlowThreshold/WithColorful
+func WithLogger(l logger.Interface) Option {
+	return func(c *OrmConfig) *OrmConfig {
+		c.Logger = &l
+		return c
+	}
+}
+
+var silentLogger = logger.Discard.LogMode(logger.Silent)
+
+// NewSilentLogger - the logger discarding everything without any overhead, which is the default
+// (except for SQLite) since we rely on Opentelemetry. The same instance is returned on each call.
+func NewSilentLogger() logger.Interface {
+	return silentLogger
+}
+
+// WithSlowThreshold - log queries slower than the given threshold
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(c *OrmConfig) *OrmConfig {
+		c.getLoggerConfig().SlowThreshold = threshold
+		return c
+	}
+}
+
+// WithColorful - enable/disable colors in the log output
+func WithColorful(colorful bool) Option {
+	return func(c *OrmConfig) *OrmConfig {
+		c.getLoggerConfig().Colorful = colorful
+		return c
+	}
+}
+
+func (c *OrmConfig) getLoggerConfig() *logger.Config {
+	if c.loggerConfig == nil {
+		// same settings as logger.Default
+		c.loggerConfig = &logger.Config{
+			SlowThreshold: 200 * time.Millisecond,
+			LogLevel:      logger.Warn,
+			Colorful:      true,
+		}
+	}
+	return c.loggerConfig
+}
+
+// A copy of the config for the Orm being created, so the state kept in it (like the pool) isn't
+// shared with the other Orm objects created from the same config
+func (c *OrmConfig) copyForOrm() *OrmConfig {
+	config := *c
+	if c.loggerConfig != nil {
+		loggerConfig := *c.loggerConfig
+		config.loggerConfig = &loggerConfig
+	}
+	config.sqlDB = nil
+	config.dialNetwork = ""
+	config.externalPool = false
+	config.poolMu = &sync.Mutex{}
+	return &config
+}
+
+func (c *OrmConfig) applyOptions(options []Option) *OrmConfig {
+	c = c.copyForOrm()
+	if c.SlowQueryThreshold != nil {
+		c.getLoggerConfig().SlowThreshold = *c.SlowQueryThreshold
+	}
+	for _, f := range options {
+		c = f(c)
+	}
+	if c.Logger == nil && c.loggerConfig != nil {
+		l := logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), *c.loggerConfig)
+		c.Logger = &l
+	}
+	if c.Logger != nil && c.SlowQueryLogsPerSecond > 0 {
+		// a cloned config has been sampled already
+		if _, sampled := (*c.Logger).(*samplingLogger); !sampled {
+			l := NewSamplingLogger(*c.Logger, c.getLoggerConfig().SlowThreshold, c.SlowQueryLogsPerSecond)
+			c.Logger = &l
+		}
+	}
+	return c
+}
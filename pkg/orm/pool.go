@@ -0,0 +1,31 @@
+package orm
+
+// SetMaxOpenConns - change the maximum number of open connections of the (primary) pool at runtime,
+// like by an autoscaler. The limit is kept by this Orm (so Clone and ResetPool use it), the config
+// given to the constructor and the other Orm objects created from it are left as they are. Safe
+// to call concurrently with ResetPool and Clone.
+func (db *Orm) SetMaxOpenConns(n int) error {
+	sqlDB, err := db.SQLDB()
+	if err != nil {
+		return err
+	}
+	db.config.poolMu.Lock()
+	defer db.config.poolMu.Unlock()
+	sqlDB.SetMaxOpenConns(n)
+	db.config.MaxOpenConns = &n // a new pointer since it may point to the default
+	return nil
+}
+
+// SetMaxIdleConns - change the maximum number of idle connections of the (primary) pool at runtime,
+// kept by this Orm like by SetMaxOpenConns (so ResetPool restores this value)
+func (db *Orm) SetMaxIdleConns(n int) error {
+	sqlDB, err := db.SQLDB()
+	if err != nil {
+		return err
+	}
+	db.config.poolMu.Lock()
+	defer db.config.poolMu.Unlock()
+	sqlDB.SetMaxIdleConns(n)
+	db.config.MaxIdleConns = &n
+	return nil
+}
@@ -0,0 +1,38 @@
+package orm
+
+import (
+	"context"
+)
+
+// CountWhere - count the rows of the model matching the conds (like gorm's Where, a query
+// followed by its args, or all rows when none), the soft deleted ones are not counted
+func (db *Orm) CountWhere(ctx context.Context, model interface{}, conds ...interface{}) (int64, error) {
+	return db.count(ctx, false, model, conds)
+}
+
+// CountWhereUnscoped - like CountWhere, but the soft deleted rows are counted as well
+func (db *Orm) CountWhereUnscoped(ctx context.Context, model interface{}, conds ...interface{}) (int64, error) {
+	return db.count(ctx, true, model, conds)
+}
+
+func (db *Orm) count(ctx context.Context, unscoped bool, model interface{}, conds []interface{}) (int64, error) {
+	tx := db.DB.WithContext(ctx).Model(model)
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+	if len(conds) > 0 {
+		tx = tx.Where(conds[0], conds[1:]...)
+	}
+
+	var count int64
+	if err := tx.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// RawScan - run the raw query with the context (so it's traced and cancelled with it) and scan
+// the result into dest
+func (db *Orm) RawScan(ctx context.Context, dest interface{}, sql string, values ...interface{}) error {
+	return db.DB.WithContext(ctx).Raw(sql, values...).Scan(dest).Error
+}
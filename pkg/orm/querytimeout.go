@@ -0,0 +1,67 @@
+package orm
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type noQueryTimeoutKey struct{}
+
+const queryTimeoutCancelKey = "orm:query_timeout_cancel"
+const queryTimeoutContextKey = "orm:query_timeout_context"
+
+// WithoutQueryTimeout - exempt the queries using the returned context from the DefaultQueryTimeout,
+// for long-running work like migrations (which the migration package exempts by itself)
+func WithoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noQueryTimeoutKey{}, true)
+}
+
+// Register callbacks giving each statement whose context has no deadline a timeout as a backstop.
+// Row/Rows are not covered since their result is read after the callbacks have completed,
+// so cancelling the context when the statement is done would break the reading.
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if callbacksRegistered(db, "orm:query_timeout_before") {
+		return nil
+	}
+
+	before := func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+		if _, hasDeadline := ctx.Deadline(); hasDeadline || ctx.Value(noQueryTimeoutKey{}) != nil {
+			return
+		}
+		tx.InstanceSet(queryTimeoutContextKey, ctx)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryTimeoutCancelKey, cancel)
+	}
+	// put the original context back, a reused chain would otherwise run on the cancelled one
+	after := func(tx *gorm.DB) {
+		if cancel, ok := tx.InstanceGet(queryTimeoutCancelKey); ok {
+			cancel.(context.CancelFunc)()
+			if ctx, ok := tx.InstanceGet(queryTimeoutContextKey); ok {
+				tx.Statement.Context = ctx.(context.Context)
+			}
+		}
+	}
+
+	callback := db.Callback()
+	for _, err := range []error{
+		callback.Create().Before("*").Register("orm:query_timeout_before", before),
+		callback.Create().After("*").Register("orm:query_timeout_after", after),
+		callback.Query().Before("*").Register("orm:query_timeout_before", before),
+		callback.Query().After("*").Register("orm:query_timeout_after", after),
+		callback.Update().Before("*").Register("orm:query_timeout_before", before),
+		callback.Update().After("*").Register("orm:query_timeout_after", after),
+		callback.Delete().Before("*").Register("orm:query_timeout_before", before),
+		callback.Delete().After("*").Register("orm:query_timeout_after", after),
+		callback.Raw().Before("*").Register("orm:query_timeout_before", before),
+		callback.Raw().After("*").Register("orm:query_timeout_after", after),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package orm
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrReadOnly - a write was attempted on a read-only Orm
+var ErrReadOnly = errors.New("orm: write rejected since the connection is read-only")
+
+// Register callbacks rejecting creates, updates, deletes and Exec statements (which covers DDL)
+// before they are executed. Row is not guarded since that's what raw queries are read with.
+func registerReadOnly(db *gorm.DB) error {
+	if callbacksRegistered(db, "orm:read_only") {
+		return nil
+	}
+
+	reject := func(tx *gorm.DB) {
+		tx.AddError(ErrReadOnly)
+	}
+
+	callback := db.Callback()
+	for _, err := range []error{
+		callback.Create().Before("*").Register("orm:read_only", reject),
+		callback.Update().Before("*").Register("orm:read_only", reject),
+		callback.Delete().Before("*").Register("orm:read_only", reject),
+		callback.Raw().Before("*").Register("orm:read_only", reject),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package orm
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// Build a dialector per replica host, the replicas share all other config values with the primary
+func replicaDialectors(
+	config *OrmConfig,
+	open func(config *OrmConfig) gorm.Dialector,
+) []gorm.Dialector {
+	dialectors := make([]gorm.Dialector, 0, len(config.ReplicaHosts))
+	for _, host := range config.ReplicaHosts {
+		replicaConfig := *config
+		replicaConfig.DbHost = host
+		dialectors = append(dialectors, open(&replicaConfig))
+	}
+	return dialectors
+}
+
+// Route reads to the replicas (round-robin) while writes and transactions go to the primary
+func useReplicas(db *gorm.DB, config *OrmConfig, replicas []gorm.Dialector) error {
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	resolver := dbresolver.
+		Register(dbresolver.Config{
+			Replicas: replicas,
+			Policy:   dbresolver.RoundRobinPolicy(),
+		}).
+		SetMaxIdleConns(*config.MaxIdleConns).
+		SetMaxOpenConns(*config.MaxOpenConns).
+		SetConnMaxLifetime(time.Duration(*config.ConnMaxLifetimeMins) * time.Minute)
+	if config.ConnMaxIdleTimeMins != nil {
+		resolver.SetConnMaxIdleTime(time.Duration(*config.ConnMaxIdleTimeMins) * time.Minute)
+	}
+
+	return usePlugin(db, resolver)
+}
@@ -0,0 +1,73 @@
+package orm
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var defaultInitialBackoff = 500 * time.Millisecond
+var defaultMaxBackoff = 30 * time.Second
+var defaultBackoffMultiplier = 2.0
+
+// ConnectRetry - configuration of the exponential backoff used when opening the connection fails
+type ConnectRetry struct {
+	MaxAttempts    int           // including the first attempt, so 1 means no retries
+	InitialBackoff time.Duration // defaults to 500ms
+	MaxBackoff     time.Duration // defaults to 30s
+	Multiplier     float64       // defaults to 2
+}
+
+func (r *ConnectRetry) setDefaults() {
+	if r.InitialBackoff <= 0 {
+		r.InitialBackoff = defaultInitialBackoff
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = defaultMaxBackoff
+	}
+	if r.Multiplier < 1 {
+		r.Multiplier = defaultBackoffMultiplier
+	}
+}
+
+// Open the connection, retrying with exponential backoff until the attempts are exhausted.
+// A new dialector is created per attempt since the pool of a failed attempt is closed.
+func openWithRetry(
+	newDialector func() gorm.Dialector,
+	gormConfig *gorm.Config,
+	retry *ConnectRetry,
+) (*gorm.DB, error) {
+	if retry == nil || retry.MaxAttempts <= 1 {
+		return gorm.Open(newDialector(), gormConfig)
+	}
+	retry.setDefaults()
+
+	backoff := retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		db, err := gorm.Open(newDialector(), gormConfig)
+		if err == nil {
+			return db, nil
+		}
+		closeQuietly(db)
+
+		if attempt >= retry.MaxAttempts {
+			return nil, err
+		}
+
+		time.Sleep(backoff)
+		backoff = time.Duration(float64(backoff) * retry.Multiplier)
+		if backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
+
+// gorm.Open returns the db even if the ping fails, so make sure the pool doesn't leak
+func closeQuietly(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
@@ -0,0 +1,81 @@
+package orm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type samplingLogger struct {
+	logger.Interface
+	slowThreshold time.Duration
+	limiter       *logLimiter
+}
+
+// NewSamplingLogger - wraps the logger to log at most perSecond failed or slow (exceeding the
+// threshold) queries per second, the others are dropped and their number logged at Warn level
+// once logging resumes. The other queries are passed on as they are, as are the Info/Warn/Error
+// messages. Setting the Info level (like Debug does) unwraps the logger to log all the queries.
+func NewSamplingLogger(l logger.Interface, slowThreshold time.Duration, perSecond int) logger.Interface {
+	return &samplingLogger{Interface: l, slowThreshold: slowThreshold, limiter: &logLimiter{perSecond: perSecond}}
+}
+
+func (s *samplingLogger) LogMode(level logger.LogLevel) logger.Interface {
+	if level >= logger.Info {
+		return s.Interface.LogMode(level)
+	}
+	return &samplingLogger{Interface: s.Interface.LogMode(level), slowThreshold: s.slowThreshold, limiter: s.limiter}
+}
+
+func (s *samplingLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if err == nil && time.Since(begin) <= s.slowThreshold {
+		s.Interface.Trace(ctx, begin, fc, err)
+		return
+	}
+
+	allowed, dropped := s.limiter.allow(time.Now())
+	if !allowed {
+		return
+	}
+	if dropped > 0 {
+		s.Interface.Warn(ctx, "%d failed or slow queries were not logged by the sampling", dropped)
+	}
+	s.Interface.Trace(ctx, begin, fc, err)
+}
+
+// ParamsFilter - pass on to the wrapped logger when it filters the query parameters
+func (s *samplingLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if filter, ok := s.Interface.(gorm.ParamsFilter); ok {
+		return filter.ParamsFilter(ctx, sql, params...)
+	}
+	return sql, params
+}
+
+// Allows perSecond calls per (fixed) one second window, counting the calls dropped in between
+type logLimiter struct {
+	mu          sync.Mutex
+	perSecond   int
+	windowStart time.Time
+	count       int
+	dropped     int
+}
+
+func (l *logLimiter) allow(now time.Time) (allowed bool, dropped int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.windowStart) >= time.Second {
+		l.windowStart = now
+		l.count = 0
+	}
+	if l.count >= l.perSecond {
+		l.dropped++
+		return false, 0
+	}
+	l.count++
+	dropped, l.dropped = l.dropped, 0
+	return true, dropped
+}
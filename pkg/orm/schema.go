@@ -0,0 +1,66 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSchemaName - the schema name passed to WithSchema isn't a plain identifier
+var ErrInvalidSchemaName = errors.New("orm: invalid schema name")
+
+var schemaName = regexp.MustCompile(`^[A-Za-z0-9_$]{1,64}$`)
+
+type schemaKey struct{}
+
+// WithSchema - returns a session where the tables of the models are qualified by the schema (like
+// `tenant_a`.`users`), so tenants living in different schemas on the same server share the pool.
+// The schema is carried by the context and survives WithContext and WithinTransaction, but it's not
+// applied to raw SQL or to the tables of joined associations. The name must be a plain identifier.
+func (db *Orm) WithSchema(name string) (*Orm, error) {
+	if !schemaName.MatchString(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSchemaName, name)
+	}
+	ctx := context.WithValue(db.Statement.Context, schemaKey{}, name)
+	return &Orm{db.DB.WithContext(ctx), db.config}, nil
+}
+
+// Keep the schema of the current context (if any) in the new one
+func (db *Orm) withSchemaOf(ctx context.Context) context.Context {
+	if name, ok := db.Statement.Context.Value(schemaKey{}).(string); ok && ctx.Value(schemaKey{}) == nil {
+		return context.WithValue(ctx, schemaKey{}, name)
+	}
+	return ctx
+}
+
+// Register the callbacks qualifying the table of the statement by the schema of the context
+func registerSchema(db *gorm.DB) error {
+	if callbacksRegistered(db, "orm:schema") {
+		return nil
+	}
+
+	qualify := func(tx *gorm.DB) {
+		name, ok := tx.Statement.Context.Value(schemaKey{}).(string)
+		if ok && tx.Statement.Table != "" && !strings.Contains(tx.Statement.Table, ".") {
+			tx.Statement.Table = name + "." + tx.Statement.Table
+		}
+	}
+
+	callback := db.Callback()
+	for _, err := range []error{
+		callback.Create().Before("*").Register("orm:schema", qualify),
+		callback.Query().Before("*").Register("orm:schema", qualify),
+		callback.Update().Before("*").Register("orm:schema", qualify),
+		callback.Delete().Before("*").Register("orm:schema", qualify),
+		callback.Row().Before("*").Register("orm:schema", qualify),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
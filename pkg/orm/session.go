@@ -0,0 +1,78 @@
+package orm
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var sessionVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// The DSN params the MySQL driver handles itself instead of running a SET, so a session variable
+// with one of these names would silently not be applied
+var mysqlDriverParams = map[string]bool{
+	"allowAllFiles": true, "allowCleartextPasswords": true, "allowFallbackToPlaintext": true,
+	"allowNativePasswords": true, "allowOldPasswords": true, "charset": true, "checkConnLiveness": true,
+	"clientFoundRows": true, "collation": true, "columnsWithAlias": true, "compress": true,
+	"connectionAttributes": true, "interpolateParams": true, "loc": true, "maxAllowedPacket": true,
+	"multiStatements": true, "parseTime": true, "readTimeout": true, "rejectReadOnly": true,
+	"serverPubKey": true, "strict": true, "timeTruncate": true, "timeout": true, "tls": true,
+	"writeTimeout": true,
+}
+
+// The session variables are set by the drivers themselves on every new connection: MySQL runs a
+// SET for each DSN param it doesn't know, and Postgres sends the unknown ones as run-time
+// parameters in the startup message. So they only have to be added to the DSN.
+
+// Add the session variables to the MySQL DSN params, the values are quoted unless numeric
+func addMySQLSessionVariables(params map[string]string, config *OrmConfig) {
+	for name, value := range config.SessionVariables {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			value = "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+		}
+		params[name] = url.QueryEscape(value)
+	}
+}
+
+// The session variables (including the SearchPath and ApplicationName) as Postgres DSN key/value
+// pairs with a leading space, sorted by name
+func postgresSessionVariables(config *OrmConfig) string {
+	variables := maps.Clone(config.SessionVariables)
+	if variables == nil {
+		variables = map[string]string{}
+	}
+	if config.SearchPath != "" {
+		variables["search_path"] = config.SearchPath
+	}
+	if config.ApplicationName != "" {
+		variables["application_name"] = config.ApplicationName
+	}
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs strings.Builder
+	for _, name := range names {
+		pairs.WriteString(" " + name + "=" + quote(variables[name]))
+	}
+	return pairs.String()
+}
+
+func validateSessionVariables(config *OrmConfig) error {
+	for name := range config.SessionVariables {
+		if !sessionVariableName.MatchString(name) {
+			return fmt.Errorf("%w: invalid session variable name %q", ErrInvalidConfig, name)
+		}
+		if mysqlDriverParams[name] {
+			return fmt.Errorf("%w: session variable %q is a MySQL driver param, set it by its OrmConfig field or DSNParams", ErrInvalidConfig, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type slogLogger struct {
+	l   *slog.Logger
+	cfg logger.Config
+}
+
+// NewSlogLogger - creates a gorm logger writing to the slog logger, with the SQL, rows affected
+// and elapsed time as structured fields. The levels map to the slog levels of the same name.
+func NewSlogLogger(l *slog.Logger, cfg logger.Config) logger.Interface {
+	return &slogLogger{l: l, cfg: cfg}
+}
+
+func (s *slogLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copy := *s
+	copy.cfg.LogLevel = level
+	return &copy
+}
+
+func (s *slogLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if s.cfg.LogLevel >= logger.Info {
+		s.l.InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (s *slogLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if s.cfg.LogLevel >= logger.Warn {
+		s.l.WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (s *slogLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if s.cfg.LogLevel >= logger.Error {
+		s.l.ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (s *slogLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if s.cfg.LogLevel <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	attrs := func() []any {
+		sql, rows := fc()
+		return []any{"sql", sql, "rows", rows, "elapsed", elapsed}
+	}
+
+	switch {
+	case err != nil && s.cfg.LogLevel >= logger.Error &&
+		(!errors.Is(err, gorm.ErrRecordNotFound) || !s.cfg.IgnoreRecordNotFoundError):
+		s.l.ErrorContext(ctx, "query failed", append(attrs(), "error", err)...)
+	case s.cfg.SlowThreshold != 0 && elapsed > s.cfg.SlowThreshold && s.cfg.LogLevel >= logger.Warn:
+		s.l.WarnContext(ctx, "slow query", append(attrs(), "slow_threshold", s.cfg.SlowThreshold)...)
+	case s.cfg.LogLevel == logger.Info:
+		s.l.InfoContext(ctx, "query", attrs()...)
+	}
+}
+
+// ParamsFilter - leave out the query parameters if ParameterizedQueries is set
+func (s *slogLogger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if s.cfg.ParameterizedQueries {
+		return sql, nil
+	}
+	return sql, params
+}
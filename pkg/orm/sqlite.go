@@ -0,0 +1,17 @@
+package orm
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// NewInMemorySQLiteDSN - a DSN for an in-memory SQLite database with a unique name, to be used
+// as SQLiteDSN when Orm instances must not share the same database (like tests running in parallel)
+func NewInMemorySQLiteDSN() string {
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("file:%s?mode=memory&cache=shared", hex.EncodeToString(name))
+}
@@ -0,0 +1,127 @@
+package orm
+
+import (
+	"context"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// ExecSQLFile - execute the statements of the SQL script at the path, see ExecSQL
+func (db *Orm) ExecSQLFile(ctx context.Context, path string) error {
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return db.ExecSQL(ctx, string(script))
+}
+
+// ExecSQL - split the SQL script into its semicolon separated statements and execute them one by
+// one within a transaction. Semicolons within quotes, comments and (Postgres) dollar quoted bodies
+// don't end a statement. Note that MySQL commits implicitly on DDL, so a failing script having DDL
+// statements can't be completely rolled back there.
+func (db *Orm) ExecSQL(ctx context.Context, script string) error {
+	statements := SplitSQLStatements(script, db.Dialector.Name())
+	return db.WithinTransaction(ctx, func(tx *Orm) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SplitSQLStatements - split the script on the semicolons outside of quotes and comments, dropping
+// the statements having nothing but comments. The dialect is the name of the gorm dialector (like
+// "mysql") since backslash escapes within quotes are only a thing in MySQL while dollar quoting is
+// Postgres only.
+func SplitSQLStatements(script string, dialect string) []string {
+	backslashEscapes := dialect == "mysql"
+	dollarQuotes := dialect == "postgres"
+
+	var statements []string
+	var current strings.Builder
+	hasContent := false
+	flush := func() {
+		if hasContent {
+			statements = append(statements, strings.TrimSpace(current.String()))
+		}
+		current.Reset()
+		hasContent = false
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == ';':
+			flush()
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(script) && script[end] != c {
+				if backslashEscapes && script[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			current.WriteString(script[i:min(end+1, len(script))])
+			i = end
+			hasContent = true
+			continue
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			current.WriteString(script[i : i+end])
+			i += end - 1
+			continue
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				end = len(script) - i
+			} else {
+				end += 4
+			}
+			current.WriteString(script[i : i+end])
+			i += end - 1
+			continue
+		case c == '$' && dollarQuotes:
+			if tag := dollarQuoteTag(script[i:]); tag != "" {
+				end := strings.Index(script[i+len(tag):], tag)
+				if end < 0 {
+					end = len(script) - i
+				} else {
+					end += 2 * len(tag)
+				}
+				current.WriteString(script[i : i+end])
+				i += end - 1
+				hasContent = true
+				continue
+			}
+		}
+		if !unicode.IsSpace(rune(c)) {
+			hasContent = true
+		}
+		current.WriteByte(c)
+	}
+	flush()
+
+	return statements
+}
+
+// The opening tag (like "$$" or "$body$") if the script starts with one
+func dollarQuoteTag(script string) string {
+	for i := 1; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == '$':
+			return script[:i+1]
+		case c == '_' || unicode.IsLetter(rune(c)) || (i > 1 && unicode.IsDigit(rune(c))):
+		default:
+			return ""
+		}
+	}
+	return ""
+}
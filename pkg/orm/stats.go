@@ -0,0 +1,75 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Stats - connection pool statistics, a zero value is returned if the pool can't be accessed
+func (db *Orm) Stats() sql.DBStats {
+	sqlDB, err := db.SQLDB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+	return sqlDB.Stats()
+}
+
+// RegisterStatsMetrics - export the connection pool statistics as Opentelemetry metrics
+func (db *Orm) RegisterStatsMetrics(meter metric.Meter) error {
+	maxOpen, err := meter.Int64ObservableGauge(
+		"db.client.connections.max",
+		metric.WithDescription("The maximum number of open connections allowed"))
+	if err != nil {
+		return err
+	}
+	open, err := meter.Int64ObservableGauge(
+		"db.client.connections.open",
+		metric.WithDescription("The number of established connections, both in use and idle"))
+	if err != nil {
+		return err
+	}
+	inUse, err := meter.Int64ObservableGauge(
+		"db.client.connections.in_use",
+		metric.WithDescription("The number of connections currently in use"))
+	if err != nil {
+		return err
+	}
+	idle, err := meter.Int64ObservableGauge(
+		"db.client.connections.idle",
+		metric.WithDescription("The number of idle connections"))
+	if err != nil {
+		return err
+	}
+	waitCount, err := meter.Int64ObservableCounter(
+		"db.client.connections.wait_count",
+		metric.WithDescription("The total number of connections waited for, climbing means MaxOpenConns is too low"))
+	if err != nil {
+		return err
+	}
+	waitDuration, err := meter.Float64ObservableCounter(
+		"db.client.connections.wait_duration",
+		metric.WithDescription("The total time blocked waiting for a new connection"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+
+	attrs := metric.WithAttributes(attribute.String("db.name", db.config.DbName))
+	_, err = meter.RegisterCallback(
+		func(_ context.Context, o metric.Observer) error {
+			stats := db.Stats()
+			o.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections), attrs)
+			o.ObserveInt64(open, int64(stats.OpenConnections), attrs)
+			o.ObserveInt64(inUse, int64(stats.InUse), attrs)
+			o.ObserveInt64(idle, int64(stats.Idle), attrs)
+			o.ObserveInt64(waitCount, stats.WaitCount, attrs)
+			o.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds(), attrs)
+			return nil
+		},
+		maxOpen, open, inUse, idle, waitCount, waitDuration,
+	)
+	return err
+}
@@ -0,0 +1,137 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+var retryBackoff = 50 * time.Millisecond
+
+// ErrNestedTransactionDisabled - WithinNestedTransaction was called with DisableNestedTransaction set
+var ErrNestedTransactionDisabled = errors.New("orm: nested transactions require DisableNestedTransaction to be false")
+
+// WithinTransaction - run fn in a transaction which is committed if fn returns nil and
+// rolled back if it returns an error or panics (the panic is propagated after the rollback)
+func (db *Orm) WithinTransaction(ctx context.Context, fn func(tx *Orm) error) error {
+	return db.withinTransaction(ctx, fn)
+}
+
+// TransactionWithIsolation - like WithinTransaction, but the transaction is started with the given
+// isolation level (like sql.LevelRepeatableRead or sql.LevelSerializable). When called on a tx the
+// level can't be changed, and fn runs within a SAVEPOINT of the outer transaction instead.
+func (db *Orm) TransactionWithIsolation(ctx context.Context, level sql.IsolationLevel, fn func(tx *Orm) error) error {
+	return db.withinTransaction(ctx, fn, &sql.TxOptions{Isolation: level})
+}
+
+func (db *Orm) withinTransaction(ctx context.Context, fn func(tx *Orm) error, options ...*sql.TxOptions) (err error) {
+	if threshold := db.config.SlowTransactionThreshold; threshold != nil {
+		start := time.Now()
+		defer func() { db.recordSlowTransaction(ctx, time.Since(start), *threshold, err) }()
+	}
+
+	return db.DB.WithContext(db.withSchemaOf(ctx)).Transaction(func(tx *gorm.DB) error {
+		txOrm := &Orm{tx, db.config}
+		tx.Statement.Context = WithTxContext(tx.Statement.Context, txOrm)
+		return fn(txOrm)
+	}, options...)
+}
+
+type txKey struct{}
+
+// WithTxContext - returns a copy of the context carrying the tx, for OrmFromContext. WithinTransaction
+// does this for the context of the tx it passes to fn (tx.Statement.Context).
+func WithTxContext(ctx context.Context, tx *Orm) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// OrmFromContext - the tx carried by the context, or the fallback (like the base connection) when
+// there is none, so helpers use the ambient transaction without it being passed along explicitly
+func OrmFromContext(ctx context.Context, fallback *Orm) *Orm {
+	if tx, ok := ctx.Value(txKey{}).(*Orm); ok {
+		return tx
+	}
+	return fallback
+}
+
+// Report a transaction which took longer than the threshold (from begin to commit/rollback) as an
+// event of the current span and as a warning, since it held its locks for that long
+func (db *Orm) recordSlowTransaction(ctx context.Context, elapsed time.Duration, threshold time.Duration, err error) {
+	if elapsed <= threshold {
+		return
+	}
+	trace.SpanFromContext(ctx).AddEvent("slow transaction", trace.WithAttributes(
+		attribute.Int64("db.transaction.duration_ms", elapsed.Milliseconds()),
+		attribute.Bool("db.transaction.committed", err == nil)))
+	if db.config.Logger != nil {
+		(*db.config.Logger).Warn(ctx, "slow transaction: %s > %s (committed: %t)", elapsed, threshold, err == nil)
+	}
+}
+
+// WithinNestedTransaction - like WithinTransaction, but when called on a tx (inside an outer
+// transaction) fn runs within a SAVEPOINT so a failure only rolls back the work done by fn, leaving
+// the outer transaction intact. Without an outer transaction a regular one is started. This relies
+// on GORM's nested transaction support so DisableNestedTransaction must be false (the default).
+func (db *Orm) WithinNestedTransaction(ctx context.Context, fn func(tx *Orm) error) error {
+	if db.DB.DisableNestedTransaction {
+		return ErrNestedTransactionDisabled
+	}
+	return db.WithinTransaction(ctx, fn)
+}
+
+// TransactionWithRetry - like WithinTransaction, but the transaction is retried (up to maxRetries
+// times, with a small increasing backoff) if it fails due to a deadlock or a lock wait timeout.
+// Any other error is returned immediately, as is any error when called on a tx.
+func (db *Orm) TransactionWithRetry(ctx context.Context, fn func(tx *Orm) error, maxRetries int) error {
+	// within an outer transaction fn runs in a SAVEPOINT, while a deadlock has rolled back the
+	// whole transaction already, so leave the retrying to the outermost caller
+	if _, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter); inTransaction {
+		maxRetries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := db.WithinTransaction(ctx, fn)
+		if err == nil || attempt >= maxRetries || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(time.Duration(attempt+1) * retryBackoff):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// Whether the error is a deadlock/lock wait timeout (or serialization failure) which is safe to retry
+func isRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1213, // ER_LOCK_DEADLOCK
+			1205: // ER_LOCK_WAIT_TIMEOUT
+			return true
+		}
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40P01", // deadlock_detected
+			"40001", // serialization_failure
+			"55P03": // lock_not_available
+			return true
+		}
+		return false
+	}
+
+	return false
+}
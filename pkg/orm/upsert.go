@@ -0,0 +1,23 @@
+package orm
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+)
+
+// Upsert - insert the value, or update the updateColumns (all the columns when empty) of the
+// existing row(s) conflicting on the conflictColumns. MySQL ignores the conflictColumns since
+// ON DUPLICATE KEY UPDATE applies to any unique key, while Postgres/SQLite use ON CONFLICT on them.
+func (db *Orm) Upsert(ctx context.Context, value interface{}, conflictColumns []string, updateColumns []string) error {
+	onConflict := clause.OnConflict{}
+	for _, column := range conflictColumns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	if len(updateColumns) == 0 {
+		onConflict.UpdateAll = true
+	} else {
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns)
+	}
+	return db.DB.WithContext(ctx).Clauses(onConflict).Create(value).Error
+}
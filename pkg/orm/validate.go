@@ -0,0 +1,42 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidConfig - a required configuration value is missing
+var ErrInvalidConfig = errors.New("orm: invalid config")
+
+// Validate - checks that the fields required to connect to a MySQL/Postgres database are set, which
+// are none when there is a RawDSN. SQLite has no required fields (it defaults to an in-memory
+// database) so it's not validated.
+func (c *OrmConfig) Validate() error {
+	if c.RawDSN != "" {
+		if len(c.ReplicaHosts) > 0 {
+			return fmt.Errorf("%w: ReplicaHosts can't be combined with RawDSN", ErrInvalidConfig)
+		}
+		return nil
+	}
+	if c.DbName == "" {
+		return missing("DbName")
+	}
+	if c.DbUser == "" {
+		return missing("DbUser")
+	}
+	if c.UseCloudSQLConnector {
+		if c.InstanceConnectionName == "" {
+			return missing("InstanceConnectionName")
+		}
+	} else if c.DbHost == "" && !c.GCPProxyTCP {
+		return missing("DbHost")
+	}
+	if c.DialFunc != nil && (c.UseCloudSQLConnector || (c.OnGCP && !c.GCPProxyTCP)) {
+		return fmt.Errorf("%w: DialFunc requires a TCP connection", ErrInvalidConfig)
+	}
+	return validateSessionVariables(c)
+}
+
+func missing(field string) error {
+	return fmt.Errorf("%w: %s is required", ErrInvalidConfig, field)
+}
@@ -0,0 +1,63 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnsupportedServerVersion - the database server is older than the MinServerVersion
+var ErrUnsupportedServerVersion = errors.New("orm: unsupported server version")
+
+var versionNumber = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// Fail unless the server version is at least the minimum version (like "8.0"),
+// the vendor specific suffixes of the version (like "-MariaDB") are ignored
+func checkServerVersion(db *gorm.DB, minVersion string) error {
+	var query string
+	switch db.Dialector.Name() {
+	case "postgres":
+		query = "SHOW server_version"
+	case "sqlserver":
+		query = "SELECT CAST(SERVERPROPERTY('ProductVersion') AS VARCHAR(128))"
+	case "sqlite":
+		query = "SELECT sqlite_version()"
+	default:
+		query = "SELECT VERSION()"
+	}
+
+	var version string
+	if err := db.Raw(query).Scan(&version).Error; err != nil {
+		return wrapErr(ErrConnect, err)
+	}
+	if compareVersions(versionNumber.FindString(version), minVersion) < 0 {
+		return fmt.Errorf("%w: %s is older than %s", ErrUnsupportedServerVersion, version, minVersion)
+	}
+	return nil
+}
+
+// Compare the dot separated version numbers part by part, the missing parts count as 0
+func compareVersions(a string, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		if diff := versionPart(aParts, i) - versionPart(bParts, i); diff != 0 {
+			if diff < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionPart(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	part, _ := strconv.Atoi(parts[i])
+	return part
+}
@@ -0,0 +1,43 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// WarmUp - pre-fill the pool by opening and pinging n connections (capped at the max number of
+// open connections), which are then all released to the pool. Note that only up to MaxIdleConns
+// of them are kept around. Returns early with the error if the context is canceled, and does
+// nothing when n isn't positive.
+func (db *Orm) WarmUp(ctx context.Context, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	sqlDB, err := db.SQLDB()
+	if err != nil {
+		return err
+	}
+	if maxOpen := sqlDB.Stats().MaxOpenConnections; maxOpen > 0 && n > maxOpen {
+		n = maxOpen
+	}
+
+	// hold on to the connections until all are opened, otherwise the same one would be reused
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	for i := 0; i < n; i++ {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		conns = append(conns, conn)
+		if err := conn.PingContext(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
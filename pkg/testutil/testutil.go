@@ -0,0 +1,35 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/dentech-floss/orm/pkg/orm"
+)
+
+// NewTestOrm - creates an isolated in-memory SQLite database with the given models migrated,
+// which is closed when the test (and all its subtests) complete
+func NewTestOrm(t testing.TB, models ...interface{}) *orm.Orm {
+	t.Helper()
+
+	// an in-memory database is deleted when its last connection is closed, so don't expire them
+	connMaxLifetimeMins := 0
+	db, err := orm.NewSQLiteOrmWithError(&orm.OrmConfig{
+		// unique per call since a shared cache name would leak data between tests
+		SQLiteDSN:           orm.NewInMemorySQLiteDSN(),
+		ConnMaxLifetimeMins: &connMaxLifetimeMins,
+	})
+	if err != nil {
+		t.Fatalf("failed to create the test database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	if len(models) > 0 {
+		if err := db.AutoMigrate(models...); err != nil {
+			t.Fatalf("failed to migrate the test database: %v", err)
+		}
+	}
+
+	return db
+}